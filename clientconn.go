@@ -0,0 +1,179 @@
+/*
+ * Copyright 2023 gRPC authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc/internal"
+	"google.golang.org/grpc/resolver"
+)
+
+func init() {
+	internal.AddExtraResolverBuilders = addExtraResolverBuilders
+	internal.ClearExtraResolverBuilders = clearExtraResolverBuilders
+	internal.RegisterResolverInterceptor = registerResolverInterceptor
+}
+
+// ClientConn represents a virtual connection to a conceptual endpoint, to
+// perform RPCs.
+type ClientConn struct {
+	target string
+	dopts  dialOptions
+
+	resolverBuilder resolver.Builder
+}
+
+// DialContext creates a ClientConn for target after applying opts.
+func DialContext(ctx context.Context, target string, opts ...DialOption) (*ClientConn, error) {
+	cc := &ClientConn{target: target}
+	for _, opt := range opts {
+		opt.apply(&cc.dopts)
+	}
+
+	rb, err := cc.parseTargetAndFindResolver()
+	if err != nil {
+		return nil, err
+	}
+	cc.resolverBuilder = rb
+
+	return cc, nil
+}
+
+// parseTargetAndFindResolver parses cc.target's scheme and resolves it to a
+// resolver.Builder via getResolverBuilder, which is what makes
+// AddExtraResolverBuilders and RegisterResolverInterceptor take effect. This
+// runs exactly once per Dial.
+func (cc *ClientConn) parseTargetAndFindResolver() (resolver.Builder, error) {
+	scheme := parseTargetScheme(cc.target)
+	rb := getResolverBuilder(scheme)
+	if rb == nil {
+		return nil, fmt.Errorf("grpc: no resolver registered for scheme %q", scheme)
+	}
+	return rb, nil
+}
+
+// parseTargetScheme extracts the scheme from a dial target of the form
+// "scheme://authority/endpoint", returning "" if target has no scheme.
+func parseTargetScheme(target string) string {
+	i := strings.Index(target, "://")
+	if i < 0 {
+		return ""
+	}
+	return target[:i]
+}
+
+var (
+	extraResolverBuildersMu sync.Mutex
+	extraResolverBuilders   []resolver.Builder
+
+	resolverInterceptorMu sync.Mutex
+	resolverInterceptor   func(resolver.Builder) resolver.Builder
+)
+
+// addExtraResolverBuilders registers builders that are made available
+// alongside the globally registered resolver.Builders for every ClientConn,
+// without requiring callers to import the scheme's package solely to
+// trigger its registration side effect.
+func addExtraResolverBuilders(builders ...resolver.Builder) {
+	extraResolverBuildersMu.Lock()
+	defer extraResolverBuildersMu.Unlock()
+	extraResolverBuilders = append(extraResolverBuilders, builders...)
+}
+
+// clearExtraResolverBuilders clears the array of extra resolver.Builder.
+// This method is useful in testing and benchmarking.
+func clearExtraResolverBuilders() {
+	extraResolverBuildersMu.Lock()
+	defer extraResolverBuildersMu.Unlock()
+	extraResolverBuilders = nil
+}
+
+// registerResolverInterceptor records interceptor so that every
+// resolver.Builder resolved for a Dial is wrapped with it before use. A
+// second call overwrites the first; unlike AddExtraResolverBuilders, which
+// accumulates, there is only ever one active interceptor. Passing nil
+// removes it.
+func registerResolverInterceptor(interceptor func(resolver.Builder) resolver.Builder) {
+	resolverInterceptorMu.Lock()
+	defer resolverInterceptorMu.Unlock()
+	resolverInterceptor = interceptor
+}
+
+// getResolverBuilder looks up the resolver.Builder for scheme, preferring
+// globally registered builders and falling back to the extra builders added
+// via AddExtraResolverBuilders, then applies the registered resolver
+// interceptor (if any) so its wrapping happens exactly once, at builder
+// resolution time during Dial.
+func getResolverBuilder(scheme string) resolver.Builder {
+	b := resolver.Get(scheme)
+	if b == nil {
+		extraResolverBuildersMu.Lock()
+		for _, rb := range extraResolverBuilders {
+			if rb.Scheme() == scheme {
+				b = rb
+				break
+			}
+		}
+		extraResolverBuildersMu.Unlock()
+	}
+	if b == nil {
+		return nil
+	}
+
+	resolverInterceptorMu.Lock()
+	interceptor := resolverInterceptor
+	resolverInterceptorMu.Unlock()
+	if interceptor == nil {
+		return b
+	}
+	return interceptor(b)
+}
+
+// interceptingResolverClientConn wraps a resolver.ClientConn so that every
+// resolver.State it receives from the wrapped resolver.Builder's Resolver is
+// passed through transform before being delivered to the real ClientConn.
+type interceptingResolverClientConn struct {
+	resolver.ClientConn
+	transform func(resolver.State) resolver.State
+}
+
+func (w *interceptingResolverClientConn) UpdateState(s resolver.State) error {
+	return w.ClientConn.UpdateState(w.transform(s))
+}
+
+// NewStateTransformingBuilder returns a resolver.Builder that wraps base so
+// that every resolver.State it produces is passed through transform (e.g. to
+// filter or rewrite addresses, or inject endpoint-level attributes) before
+// reaching the ClientConn. It is intended for use as (part of) the
+// interceptor function passed to RegisterResolverInterceptor.
+func NewStateTransformingBuilder(base resolver.Builder, transform func(resolver.State) resolver.State) resolver.Builder {
+	return &stateTransformingBuilder{Builder: base, transform: transform}
+}
+
+type stateTransformingBuilder struct {
+	resolver.Builder
+	transform func(resolver.State) resolver.State
+}
+
+func (b *stateTransformingBuilder) Build(target resolver.Target, cc resolver.ClientConn, opts resolver.BuildOptions) (resolver.Resolver, error) {
+	return b.Builder.Build(target, &interceptingResolverClientConn{ClientConn: cc, transform: b.transform}, opts)
+}
@@ -0,0 +1,160 @@
+/*
+ * Copyright 2023 gRPC authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/resolver"
+)
+
+type fakeResolver struct{}
+
+func (fakeResolver) ResolveNow(resolver.ResolveNowOptions) {}
+func (fakeResolver) Close()                                {}
+
+type fakeBuilder struct {
+	scheme string
+}
+
+func (b *fakeBuilder) Scheme() string { return b.scheme }
+func (b *fakeBuilder) Build(resolver.Target, resolver.ClientConn, resolver.BuildOptions) (resolver.Resolver, error) {
+	return fakeResolver{}, nil
+}
+
+func TestGetResolverBuilderFromExtraBuilders(t *testing.T) {
+	clearExtraResolverBuilders()
+	defer clearExtraResolverBuilders()
+
+	addExtraResolverBuilders(&fakeBuilder{scheme: "extra-scheme"})
+
+	if got := getResolverBuilder("extra-scheme"); got == nil {
+		t.Fatal("getResolverBuilder(\"extra-scheme\") = nil, want non-nil")
+	}
+	if got := getResolverBuilder("does-not-exist-scheme"); got != nil {
+		t.Fatalf("getResolverBuilder(\"does-not-exist-scheme\") = %v, want nil", got)
+	}
+}
+
+func TestRegisterResolverInterceptorWrapsBuilder(t *testing.T) {
+	clearExtraResolverBuilders()
+	defer clearExtraResolverBuilders()
+	defer registerResolverInterceptor(nil)
+
+	addExtraResolverBuilders(&fakeBuilder{scheme: "intercepted-scheme"})
+
+	var wrapped bool
+	registerResolverInterceptor(func(b resolver.Builder) resolver.Builder {
+		wrapped = true
+		return b
+	})
+
+	if got := getResolverBuilder("intercepted-scheme"); got == nil {
+		t.Fatal("getResolverBuilder(\"intercepted-scheme\") = nil, want non-nil")
+	}
+	if !wrapped {
+		t.Error("registered interceptor was not invoked")
+	}
+}
+
+// updatingBuilder calls UpdateState on the resolver.ClientConn it is given
+// as soon as it is built, so tests can observe what the ClientConn receives
+// after any interceptor has run.
+type updatingBuilder struct {
+	scheme string
+}
+
+func (b *updatingBuilder) Scheme() string { return b.scheme }
+func (b *updatingBuilder) Build(_ resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	if err := cc.UpdateState(resolver.State{}); err != nil {
+		return nil, err
+	}
+	return fakeResolver{}, nil
+}
+
+func TestNewStateTransformingBuilderTransformsState(t *testing.T) {
+	cc := &recordingClientConn{}
+	b := NewStateTransformingBuilder(&updatingBuilder{scheme: "transform-scheme"}, func(s resolver.State) resolver.State {
+		s.Attributes = s.Attributes.WithValue("transformed", true)
+		return s
+	})
+
+	if _, err := b.Build(resolver.Target{}, cc, resolver.BuildOptions{}); err != nil {
+		t.Fatalf("Build() returned error: %v", err)
+	}
+	if cc.lastState.Attributes == nil || cc.lastState.Attributes.Value("transformed") != true {
+		t.Error("ClientConn did not observe the transformed resolver.State")
+	}
+}
+
+type recordingClientConn struct {
+	resolver.ClientConn
+	lastState resolver.State
+}
+
+func (r *recordingClientConn) UpdateState(s resolver.State) error {
+	r.lastState = s
+	return nil
+}
+
+func TestDialContextUsesExtraResolverBuilder(t *testing.T) {
+	clearExtraResolverBuilders()
+	defer clearExtraResolverBuilders()
+
+	addExtraResolverBuilders(&fakeBuilder{scheme: "extra-dial-scheme"})
+
+	cc, err := DialContext(context.Background(), "extra-dial-scheme:///target")
+	if err != nil {
+		t.Fatalf("DialContext() returned error: %v", err)
+	}
+	if cc.resolverBuilder == nil {
+		t.Fatal("cc.resolverBuilder = nil, want the extra builder")
+	}
+}
+
+func TestDialContextAppliesResolverInterceptor(t *testing.T) {
+	clearExtraResolverBuilders()
+	defer clearExtraResolverBuilders()
+	defer registerResolverInterceptor(nil)
+
+	addExtraResolverBuilders(&fakeBuilder{scheme: "intercepted-dial-scheme"})
+
+	var intercepted resolver.Builder
+	registerResolverInterceptor(func(b resolver.Builder) resolver.Builder {
+		intercepted = b
+		return b
+	})
+
+	cc, err := DialContext(context.Background(), "intercepted-dial-scheme:///target")
+	if err != nil {
+		t.Fatalf("DialContext() returned error: %v", err)
+	}
+	if intercepted == nil {
+		t.Fatal("the resolver interceptor registered via RegisterResolverInterceptor was not invoked by DialContext")
+	}
+	if cc.resolverBuilder != intercepted {
+		t.Error("cc.resolverBuilder is not the (possibly wrapped) builder returned by the interceptor")
+	}
+}
+
+func TestDialContextUnknownSchemeFails(t *testing.T) {
+	if _, err := DialContext(context.Background(), "no-such-scheme:///target"); err == nil {
+		t.Fatal("DialContext() with an unregistered scheme returned nil error, want non-nil")
+	}
+}
@@ -0,0 +1,70 @@
+/*
+ * Copyright 2023 gRPC authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package grpc
+
+import (
+	"google.golang.org/grpc/internal"
+	"google.golang.org/grpc/internal/transport"
+)
+
+func init() {
+	internal.WithRecvBufferPool = WithRecvBufferPool
+}
+
+// DialOption configures how a ClientConn is set up when it is dialed.
+type DialOption interface {
+	apply(*dialOptions)
+}
+
+// dialOptions holds the state configured by DialOptions. Each ClientConn
+// owns exactly one, built up by applying every DialOption passed to Dial.
+type dialOptions struct {
+	copts transport.ConnectOptions
+}
+
+type funcDialOption struct {
+	f func(*dialOptions)
+}
+
+func (fdo *funcDialOption) apply(do *dialOptions) {
+	fdo.f(do)
+}
+
+func newFuncDialOption(f func(*dialOptions)) *funcDialOption {
+	return &funcDialOption{f: f}
+}
+
+// WithRecvBufferPool returns a DialOption that configures the ClientConn
+// to use the provided shared buffer pool for its inbound message buffers.
+// Using a shared buffer pool can significantly reduce memory allocation,
+// especially for large or bursty message payloads.
+//
+// Buffers handed out by the pool are returned (and may be reused for a
+// later message) once the codec has finished unmarshalling a given
+// message, so a codec or application must not retain a reference to the
+// unmarshaled message's backing buffer past the call to Unmarshal.
+//
+// # Experimental
+//
+// Notice: This API is EXPERIMENTAL and may be changed or removed in a
+// later release.
+func WithRecvBufferPool(bufferPool transport.SharedBufferPool) DialOption {
+	return newFuncDialOption(func(o *dialOptions) {
+		o.copts.RecvBufferPool = bufferPool
+	})
+}
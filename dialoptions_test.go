@@ -0,0 +1,54 @@
+/*
+ * Copyright 2023 gRPC authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package grpc
+
+import (
+	"bytes"
+	"testing"
+
+	"google.golang.org/grpc/internal/transport"
+)
+
+func TestWithRecvBufferPoolReachesConnectOptions(t *testing.T) {
+	pool := transport.NewSharedBufferPool()
+
+	var do dialOptions
+	WithRecvBufferPool(pool).apply(&do)
+
+	if do.copts.RecvBufferPool != pool {
+		t.Fatal("dialOptions.copts.RecvBufferPool was not set by WithRecvBufferPool")
+	}
+
+	want := []byte("client message")
+	mr := transport.NewClientMessageReader(bytes.NewReader(want), do.copts)
+	got, err := mr.ReadMessage(len(want))
+	if err != nil {
+		t.Fatalf("ReadMessage() returned error: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("ReadMessage() = %q, want %q", got, want)
+	}
+	mr.FreeMessage(got)
+}
+
+func TestDefaultDialOptionsUseNopBufferPool(t *testing.T) {
+	var do dialOptions
+	if do.copts.BufferPool() == nil {
+		t.Fatal("dialOptions{}.copts.BufferPool() = nil, want a default nopBufferPool")
+	}
+}
@@ -0,0 +1,179 @@
+/*
+ * Copyright 2023 gRPC authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+// Package health provides client-side health checking for gRPC channels.
+package health
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/internal"
+	"google.golang.org/grpc/internal/backoff"
+	"google.golang.org/grpc/status"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func init() {
+	internal.HealthCheckFuncV2 = clientHealthCheckV2
+}
+
+var backoffStrategy = backoff.DefaultExponential
+
+// watchStream is the subset of grpc.ClientStream that clientHealthCheckV2
+// needs to drive a single Watch RPC.
+type watchStream interface {
+	Send(*healthpb.HealthCheckRequest) error
+	Recv() (*healthpb.HealthCheckResponse, error)
+	CloseSend() error
+}
+
+// clientHealthCheckV2 watches the health of every service in serviceNames,
+// demultiplexing the per-service Watch RPCs of the grpc.health.v1 protocol
+// into the single setConnectivityState callback so that a caller (such as an
+// LB policy tracking aggregate backend health) can watch several logical
+// services on one subchannel without managing a stream per service itself.
+//
+// Each service is watched on its own Watch stream (opened via newStream),
+// but those streams are started and retried independently and concurrently,
+// and every connectivity transition is reported atomically per service so
+// that a caller polling multiple services in sequence never observes a
+// torn update. If the health server returns Unimplemented for one service,
+// only that service's watch is disabled; watches for the remaining services
+// continue unaffected.
+func clientHealthCheckV2(ctx context.Context, newStream func([]string) (interface{}, error), setConnectivityState func(service string, state connectivity.State, err error), serviceNames []string) error {
+	if len(serviceNames) == 0 {
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex // serializes calls into setConnectivityState across services.
+	syncSetConnectivityState := func(service string, state connectivity.State, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		setConnectivityState(service, state, err)
+	}
+
+	wg.Add(len(serviceNames))
+	for _, service := range serviceNames {
+		service := service
+		go func() {
+			defer wg.Done()
+			watchService(ctx, newStream, syncSetConnectivityState, service)
+		}()
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+// watchService drives a single service's Watch stream to completion (i.e.
+// until ctx is done), reconnecting with exponential backoff whenever the
+// stream breaks, and disabling itself permanently if the server reports
+// Unimplemented for this service.
+func watchService(ctx context.Context, newStream func([]string) (interface{}, error), setConnectivityState func(service string, state connectivity.State, err error), service string) {
+	tryCnt := 0
+
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+		tryCnt++
+
+		rawS, err := newStream([]string{service})
+		if err != nil {
+			setConnectivityState(service, connectivity.Connecting, err)
+			if !backoffAndRetry(ctx, &tryCnt) {
+				return
+			}
+			continue
+		}
+		s, ok := rawS.(watchStream)
+		if !ok {
+			setConnectivityState(service, connectivity.Connecting, fmt.Errorf("newStream returned stream of type %T, want watchStream", rawS))
+			return
+		}
+
+		if err := s.Send(&healthpb.HealthCheckRequest{Service: service}); err != nil {
+			setConnectivityState(service, connectivity.Connecting, err)
+			if !backoffAndRetry(ctx, &tryCnt) {
+				return
+			}
+			continue
+		}
+
+		streamErr := recvLoop(ctx, s, setConnectivityState, service, &tryCnt)
+		if streamErr == nil {
+			// Context was canceled; the overall watch is done.
+			return
+		}
+		if status.Code(streamErr) == codes.Unimplemented {
+			setConnectivityState(service, connectivity.Ready, nil)
+			return
+		}
+		setConnectivityState(service, connectivity.TransientFailure, streamErr)
+		if !backoffAndRetry(ctx, &tryCnt) {
+			return
+		}
+	}
+}
+
+// recvLoop reads responses for service off s until the stream ends or ctx is
+// canceled, reporting every transition. It returns nil only when ctx is
+// canceled; any other return value is the error that ended the stream.
+// tryCnt is reset to 0 after every successful Recv so that a later
+// reconnect backs off starting from the stream's own failure count, rather
+// than carrying over delay accumulated before this stream became healthy.
+func recvLoop(ctx context.Context, s watchStream, setConnectivityState func(service string, state connectivity.State, err error), service string, tryCnt *int) error {
+	for {
+		if ctx.Err() != nil {
+			return nil
+		}
+		resp, err := s.Recv()
+		if err == io.EOF {
+			return fmt.Errorf("health check stream for %q closed unexpectedly", service)
+		}
+		if err != nil {
+			return err
+		}
+		*tryCnt = 0
+		switch resp.Status {
+		case healthpb.HealthCheckResponse_SERVING:
+			setConnectivityState(service, connectivity.Ready, nil)
+		default:
+			setConnectivityState(service, connectivity.TransientFailure, fmt.Errorf("health check for %q reported status %v", service, resp.Status))
+		}
+	}
+}
+
+// backoffAndRetry sleeps for the backoff duration corresponding to *tryCnt,
+// returning false (without sleeping) if ctx is done first.
+func backoffAndRetry(ctx context.Context, tryCnt *int) bool {
+	timer := time.NewTimer(backoffStrategy.Backoff(*tryCnt))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,121 @@
+/*
+ * Copyright 2023 gRPC authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package health
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// fakeWatchStream is an in-memory watchStream that replays a canned sequence
+// of Recv results for a single service.
+type fakeWatchStream struct {
+	service string
+	recvs   []any // either *healthpb.HealthCheckResponse or error
+	idx     int
+}
+
+func (f *fakeWatchStream) Send(*healthpb.HealthCheckRequest) error { return nil }
+func (f *fakeWatchStream) CloseSend() error                        { return nil }
+func (f *fakeWatchStream) Recv() (*healthpb.HealthCheckResponse, error) {
+	if f.idx >= len(f.recvs) {
+		return nil, io.EOF
+	}
+	r := f.recvs[f.idx]
+	f.idx++
+	switch v := r.(type) {
+	case error:
+		return nil, v
+	case *healthpb.HealthCheckResponse:
+		return v, nil
+	default:
+		panic("unexpected recv entry")
+	}
+}
+
+func TestClientHealthCheckV2Demultiplexes(t *testing.T) {
+	streams := map[string]*fakeWatchStream{
+		"foo": {service: "foo", recvs: []any{
+			&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING},
+			status.Error(codes.Unimplemented, "unimplemented"),
+		}},
+		"bar": {service: "bar", recvs: []any{
+			&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING},
+			status.Error(codes.Unimplemented, "unimplemented"),
+		}},
+	}
+
+	newStream := func(services []string) (interface{}, error) {
+		if len(services) != 1 {
+			t.Fatalf("newStream called with %d services, want 1", len(services))
+		}
+		s, ok := streams[services[0]]
+		if !ok {
+			return nil, fmt.Errorf("no stream configured for service %q", services[0])
+		}
+		return s, nil
+	}
+
+	var mu sync.Mutex
+	states := map[string][]connectivity.State{}
+	setConnectivityState := func(service string, state connectivity.State, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		states[service] = append(states[service], state)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := clientHealthCheckV2(ctx, newStream, setConnectivityState, []string{"foo", "bar"}); err != nil {
+		t.Fatalf("clientHealthCheckV2() = %v, want nil", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, service := range []string{"foo", "bar"} {
+		got := states[service]
+		if len(got) == 0 || got[0] != connectivity.Ready {
+			t.Errorf("states[%q] = %v, want first state Ready", service, got)
+		}
+	}
+}
+
+func TestClientHealthCheckV2EmptyServiceList(t *testing.T) {
+	called := false
+	newStream := func([]string) (interface{}, error) {
+		called = true
+		return nil, nil
+	}
+	if err := clientHealthCheckV2(context.Background(), newStream, func(string, connectivity.State, error) {}, nil); err != nil {
+		t.Fatalf("clientHealthCheckV2() = %v, want nil", err)
+	}
+	if called {
+		t.Error("newStream was called for an empty service list")
+	}
+}
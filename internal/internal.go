@@ -33,6 +33,12 @@ var (
 	WithHealthCheckFunc interface{} // func (HealthChecker) DialOption
 	// HealthCheckFunc is used to provide client-side LB channel health checking
 	HealthCheckFunc HealthChecker
+	// HealthCheckFuncV2 is used to provide client-side LB channel health
+	// checking for multiple service names at once (one independent Watch
+	// stream per service, aggregated behind a single callback). This is set
+	// by health/client.go and is kept alongside HealthCheckFunc for backward
+	// compatibility with callers that only watch a single service.
+	HealthCheckFuncV2 HealthCheckerV2
 	// BalancerUnregister is exported by package balancer to unregister a balancer.
 	BalancerUnregister func(name string)
 	// KeepaliveMinPingTime is the minimum ping interval.  This must be 10s by
@@ -77,6 +83,24 @@ var (
 	// ClearExtraDialOptions clears the array of extra DialOption. This
 	// method is useful in testing and benchmarking.
 	ClearExtraDialOptions func()
+	// WithRecvBufferPool is set by dialoptions.go
+	WithRecvBufferPool interface{} // func (transport.SharedBufferPool) DialOption
+	// RecvBufferPool is set by server.go
+	RecvBufferPool interface{} // func (transport.SharedBufferPool) ServerOption
+
+	// AddExtraResolverBuilders adds an array of resolver.Builder that will be
+	// globally registered alongside the default builders for newly created
+	// client channels. This is set by clientconn.go.
+	AddExtraResolverBuilders interface{} // func(builders ...resolver.Builder)
+	// ClearExtraResolverBuilders clears the array of extra resolver.Builder.
+	// This method is useful in testing and benchmarking.
+	ClearExtraResolverBuilders func()
+	// RegisterResolverInterceptor registers a function that wraps every
+	// resolver.Builder's Build result so that the resulting resolver.State can
+	// be transformed (e.g. to filter addresses, rewrite endpoint weights, or
+	// inject locality attributes) before it reaches the ClientConn. This is
+	// set by clientconn.go and is applied once per Dial.
+	RegisterResolverInterceptor interface{} // func(interceptor func(resolver.Builder) resolver.Builder)
 
 	// NewXDSResolverWithConfigForTesting creates a new xds resolver builder using
 	// the provided xds bootstrap config instead of the global configuration from
@@ -129,6 +153,26 @@ var (
 // https://github.com/grpc/grpc/blob/master/doc/health-checking.md
 type HealthChecker func(ctx context.Context, newStream func(string) (interface{}, error), setConnectivityState func(connectivity.State, error), serviceName string) error
 
+// HealthCheckerV2 defines the signature of the client-side LB channel health
+// checking function that can watch multiple service names at once.
+//
+// The implementation is expected to create a health checking RPC stream
+// (via newStream) per name in serviceNames — the grpc.health.v1 Watch RPC
+// is itself single-service, so there is one independent stream per watched
+// service, not one stream multiplexing all of them — and report health
+// back independently per service by calling setConnectivityState().
+// Implementations should use exponential backoff to reconnect a given
+// service's stream when it breaks, and should stop watching (and report as
+// Ready) a service for which the health server returns Unimplemented,
+// without affecting the streams for any other watched service, and must
+// ensure that each call to setConnectivityState() reflects a single,
+// atomic transition for that service so that watchers never observe a
+// torn update.
+//
+// The health checking protocol is defined at:
+// https://github.com/grpc/grpc/blob/master/doc/health-checking.md
+type HealthCheckerV2 func(ctx context.Context, newStream func([]string) (interface{}, error), setConnectivityState func(service string, state connectivity.State, err error), serviceNames []string) error
+
 const (
 	// CredsBundleModeFallback switches GoogleDefaultCreds to fallback mode.
 	CredsBundleModeFallback = "fallback"
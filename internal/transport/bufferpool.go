@@ -0,0 +1,128 @@
+/*
+ * Copyright 2023 gRPC authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package transport
+
+import "sync"
+
+// SharedBufferPool is a pool of buffers that can be shared, resulting in
+// decreased memory allocation. Currently, in order to use the shared buffer
+// pool, the caller must be using gRPC's built-in codec and have its
+// messages read straight off the wire into the pool's buffers, as opposed to
+// being copied into an intermediate buffer first.
+type SharedBufferPool interface {
+	// Get returns a buffer with specified length from the pool.
+	//
+	// The returned byte slice may be not zero initialized.
+	Get(length int) []byte
+
+	// Put returns a buffer to the pool.
+	Put(*[]byte)
+}
+
+// NewSharedBufferPool creates a simple SharedBufferPool with buckets
+// of different sizes to optimize memory usage. This prevents the pool from
+// wasting memory even when handling messages of varying sizes.
+func NewSharedBufferPool() SharedBufferPool {
+	return &simpleSharedBufferPool{
+		pools: [...]*sizedBufferPool{
+			newSizedBufferPool(level0PoolBufferSize),
+			newSizedBufferPool(level1PoolBufferSize),
+			newSizedBufferPool(level2PoolBufferSize),
+		},
+	}
+}
+
+// simpleSharedBufferPool is a simple implementation of SharedBufferPool.
+// It selects the appropriately sized pool for the requested length, falling
+// back to direct allocation for sizes that exceed every pool's bucket size.
+type simpleSharedBufferPool struct {
+	pools [3]*sizedBufferPool
+}
+
+func (p *simpleSharedBufferPool) Get(size int) []byte {
+	return p.pool(size).Get(size)
+}
+
+func (p *simpleSharedBufferPool) Put(bs *[]byte) {
+	p.pool(cap(*bs)).Put(bs)
+}
+
+func (p *simpleSharedBufferPool) pool(size int) *sizedBufferPool {
+	switch {
+	case size <= level0PoolBufferSize:
+		return p.pools[0]
+	case size <= level1PoolBufferSize:
+		return p.pools[1]
+	default:
+		return p.pools[2]
+	}
+}
+
+const (
+	level0PoolBufferSize = 2 * 1024
+	level1PoolBufferSize = 16 * 1024
+	level2PoolBufferSize = 64 * 1024
+)
+
+// sizedBufferPool is a sync.Pool that hands out slices of a fixed capacity.
+// Like any sync.Pool, the runtime is free to reclaim pooled slices between
+// GCs, so it doesn't pin an unbounded amount of memory even under a burst of
+// large messages.
+type sizedBufferPool struct {
+	pool       sync.Pool
+	bufferSize int
+}
+
+func newSizedBufferPool(bufferSize int) *sizedBufferPool {
+	return &sizedBufferPool{
+		pool: sync.Pool{
+			New: func() any {
+				bs := make([]byte, bufferSize)
+				return &bs
+			},
+		},
+		bufferSize: bufferSize,
+	}
+}
+
+func (p *sizedBufferPool) Get(size int) []byte {
+	if size > p.bufferSize {
+		return make([]byte, size)
+	}
+	bs := p.pool.Get().(*[]byte)
+	b := (*bs)[:size]
+	return b
+}
+
+func (p *sizedBufferPool) Put(bs *[]byte) {
+	if cap(*bs) < p.bufferSize {
+		return
+	}
+	p.pool.Put(bs)
+}
+
+// nopBufferPool is a buffer pool that returns freshly allocated (and never
+// reused) buffers. It is used as the default pool so that behavior is
+// unchanged when the user hasn't configured a SharedBufferPool.
+type nopBufferPool struct{}
+
+func (nopBufferPool) Get(length int) []byte {
+	return make([]byte, length)
+}
+
+func (nopBufferPool) Put(*[]byte) {}
@@ -0,0 +1,52 @@
+/*
+ * Copyright 2023 gRPC authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package transport
+
+import "testing"
+
+func TestNopBufferPool(t *testing.T) {
+	p := nopBufferPool{}
+	b := p.Get(10)
+	if len(b) != 10 {
+		t.Fatalf("Get(10) returned slice of length %d, want 10", len(b))
+	}
+	// Put should be a no-op and never panic.
+	p.Put(&b)
+}
+
+func TestSharedBufferPoolReturnsRequestedLength(t *testing.T) {
+	pool := NewSharedBufferPool()
+	for _, size := range []int{1, level0PoolBufferSize, level0PoolBufferSize + 1, level1PoolBufferSize + 1, level2PoolBufferSize * 2} {
+		b := pool.Get(size)
+		if len(b) != size {
+			t.Fatalf("Get(%d) returned slice of length %d, want %d", size, len(b), size)
+		}
+		pool.Put(&b)
+	}
+}
+
+func TestSharedBufferPoolReuse(t *testing.T) {
+	pool := NewSharedBufferPool()
+	b := pool.Get(level0PoolBufferSize)
+	pool.Put(&b)
+
+	b2 := pool.Get(level0PoolBufferSize)
+	if cap(b2) < level0PoolBufferSize {
+		t.Fatalf("Get(%d) returned slice of cap %d, want at least %d", level0PoolBufferSize, cap(b2), level0PoolBufferSize)
+	}
+}
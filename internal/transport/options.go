@@ -0,0 +1,56 @@
+/*
+ * Copyright 2023 gRPC authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package transport
+
+// ConnectOptions covers the options used by a ClientConn when creating a
+// transport to a server.
+type ConnectOptions struct {
+	// RecvBufferPool is the buffer pool used to allocate the byte slices
+	// that inbound message frames are read into on this transport. If nil,
+	// a nopBufferPool is used and every frame is allocated fresh, matching
+	// the pre-pool behavior.
+	RecvBufferPool SharedBufferPool
+}
+
+// BufferPool returns co.RecvBufferPool, or a nopBufferPool if it is unset,
+// so that read-path code never has to nil-check it.
+func (co ConnectOptions) BufferPool() SharedBufferPool {
+	if co.RecvBufferPool == nil {
+		return nopBufferPool{}
+	}
+	return co.RecvBufferPool
+}
+
+// ServerConfig consists of all the configuration to construct a server
+// transport for an accepted connection.
+type ServerConfig struct {
+	// RecvBufferPool is the buffer pool used to allocate the byte slices
+	// that inbound message frames are read into on transports accepted by
+	// this server. If nil, a nopBufferPool is used and every frame is
+	// allocated fresh, matching the pre-pool behavior.
+	RecvBufferPool SharedBufferPool
+}
+
+// BufferPool returns sc.RecvBufferPool, or a nopBufferPool if it is unset,
+// so that read-path code never has to nil-check it.
+func (sc ServerConfig) BufferPool() SharedBufferPool {
+	if sc.RecvBufferPool == nil {
+		return nopBufferPool{}
+	}
+	return sc.RecvBufferPool
+}
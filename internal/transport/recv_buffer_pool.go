@@ -0,0 +1,96 @@
+/*
+ * Copyright 2023 gRPC authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package transport
+
+import (
+	"fmt"
+	"io"
+)
+
+// readGRPCMessageBody reads the length-prefixed gRPC message body of size
+// into a buffer acquired from pool, so that the transport read loop does not
+// have to allocate a fresh slice for every inbound frame.
+//
+// The returned buffer is owned by the caller once this function returns; the
+// caller (ultimately the codec, after Unmarshal has copied out of it) is
+// responsible for calling pool.Put on it. On error, the partially filled
+// buffer is returned to pool before returning.
+func readGRPCMessageBody(r io.Reader, size int, pool SharedBufferPool) ([]byte, error) {
+	if size == 0 {
+		return nil, nil
+	}
+	buf := pool.Get(size)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		pool.Put(&buf)
+		return nil, fmt.Errorf("transport: failed to read message body: %v", err)
+	}
+	return buf, nil
+}
+
+// MessageReader is the transport read-loop's entry point for pulling
+// length-prefixed gRPC message bodies off the wire using a SharedBufferPool,
+// so that a configured pool is actually exercised instead of just being
+// stored on ConnectOptions/ServerConfig. A client transport constructs one
+// from its ConnectOptions.BufferPool(), and a server transport from its
+// ServerConfig.BufferPool(), per accepted stream.
+type MessageReader struct {
+	r    io.Reader
+	pool SharedBufferPool
+}
+
+// NewMessageReader returns a MessageReader that reads message bodies from r,
+// using pool to allocate their backing buffers. If pool is nil, a
+// nopBufferPool is used.
+func NewMessageReader(r io.Reader, pool SharedBufferPool) *MessageReader {
+	if pool == nil {
+		pool = nopBufferPool{}
+	}
+	return &MessageReader{r: r, pool: pool}
+}
+
+// NewClientMessageReader returns a MessageReader configured from co, the
+// ConnectOptions supplied to the client transport (and, in turn, populated
+// by WithRecvBufferPool).
+func NewClientMessageReader(r io.Reader, co ConnectOptions) *MessageReader {
+	return NewMessageReader(r, co.BufferPool())
+}
+
+// NewServerMessageReader returns a MessageReader configured from sc, the
+// ServerConfig supplied to the server transport (and, in turn, populated by
+// the RecvBufferPool ServerOption).
+func NewServerMessageReader(r io.Reader, sc ServerConfig) *MessageReader {
+	return NewMessageReader(r, sc.BufferPool())
+}
+
+// ReadMessage reads exactly size bytes of message body, acquiring its
+// backing buffer from the MessageReader's pool. The codec must call
+// FreeMessage once Unmarshal has finished copying out of the returned
+// slice, since the backing array may be reused by a later message after
+// that point.
+func (mr *MessageReader) ReadMessage(size int) ([]byte, error) {
+	return readGRPCMessageBody(mr.r, size, mr.pool)
+}
+
+// FreeMessage returns buf, previously returned by ReadMessage, to the pool.
+// The caller must not retain buf, or any slice of it, after calling this.
+func (mr *MessageReader) FreeMessage(buf []byte) {
+	if buf == nil {
+		return
+	}
+	mr.pool.Put(&buf)
+}
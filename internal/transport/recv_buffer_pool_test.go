@@ -0,0 +1,122 @@
+/*
+ * Copyright 2023 gRPC authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package transport
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestReadGRPCMessageBody(t *testing.T) {
+	pool := NewSharedBufferPool()
+	want := []byte("hello world")
+	got, err := readGRPCMessageBody(bytes.NewReader(want), len(want), pool)
+	if err != nil {
+		t.Fatalf("readGRPCMessageBody() returned error: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("readGRPCMessageBody() = %q, want %q", got, want)
+	}
+	pool.Put(&got)
+}
+
+func TestReadGRPCMessageBodyShortRead(t *testing.T) {
+	pool := NewSharedBufferPool()
+	if _, err := readGRPCMessageBody(bytes.NewReader([]byte("abc")), 10, pool); err == nil {
+		t.Fatal("readGRPCMessageBody() with truncated input returned nil error, want non-nil")
+	}
+}
+
+func TestReadGRPCMessageBodyZeroLength(t *testing.T) {
+	pool := NewSharedBufferPool()
+	got, err := readGRPCMessageBody(bytes.NewReader(nil), 0, pool)
+	if err != nil {
+		t.Fatalf("readGRPCMessageBody() returned error: %v", err)
+	}
+	if got != nil {
+		t.Fatalf("readGRPCMessageBody() = %v, want nil", got)
+	}
+}
+
+// countingPool wraps a SharedBufferPool and counts Get/Put calls, so tests
+// can assert that a pool configured on ConnectOptions/ServerConfig is
+// actually reached by the read path, not just stored.
+type countingPool struct {
+	SharedBufferPool
+	gets, puts int
+}
+
+func (p *countingPool) Get(length int) []byte {
+	p.gets++
+	return p.SharedBufferPool.Get(length)
+}
+
+func (p *countingPool) Put(bs *[]byte) {
+	p.puts++
+	p.SharedBufferPool.Put(bs)
+}
+
+func TestClientMessageReaderUsesConnectOptionsPool(t *testing.T) {
+	pool := &countingPool{SharedBufferPool: NewSharedBufferPool()}
+	want := []byte("end to end")
+	mr := NewClientMessageReader(bytes.NewReader(want), ConnectOptions{RecvBufferPool: pool})
+
+	got, err := mr.ReadMessage(len(want))
+	if err != nil {
+		t.Fatalf("ReadMessage() returned error: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("ReadMessage() = %q, want %q", got, want)
+	}
+	if pool.gets != 1 {
+		t.Errorf("pool.gets = %d, want 1", pool.gets)
+	}
+	mr.FreeMessage(got)
+	if pool.puts != 1 {
+		t.Errorf("pool.puts = %d, want 1", pool.puts)
+	}
+}
+
+func TestServerMessageReaderUsesServerConfigPool(t *testing.T) {
+	pool := &countingPool{SharedBufferPool: NewSharedBufferPool()}
+	want := []byte("server side")
+	mr := NewServerMessageReader(bytes.NewReader(want), ServerConfig{RecvBufferPool: pool})
+
+	got, err := mr.ReadMessage(len(want))
+	if err != nil {
+		t.Fatalf("ReadMessage() returned error: %v", err)
+	}
+	mr.FreeMessage(got)
+	if pool.gets != 1 || pool.puts != 1 {
+		t.Errorf("pool.gets = %d, pool.puts = %d, want 1, 1", pool.gets, pool.puts)
+	}
+}
+
+func TestMessageReaderDefaultsToNopBufferPool(t *testing.T) {
+	want := []byte("no pool configured")
+	mr := NewClientMessageReader(bytes.NewReader(want), ConnectOptions{})
+
+	got, err := mr.ReadMessage(len(want))
+	if err != nil {
+		t.Fatalf("ReadMessage() returned error: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("ReadMessage() = %q, want %q", got, want)
+	}
+	mr.FreeMessage(got) // Must not panic even though no pool was configured.
+}
@@ -0,0 +1,95 @@
+/*
+ * Copyright 2023 gRPC authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package grpc
+
+import (
+	"google.golang.org/grpc/internal"
+	"google.golang.org/grpc/internal/transport"
+)
+
+func init() {
+	internal.RecvBufferPool = RecvBufferPool
+}
+
+// ServerOption configures how a Server is set up.
+type ServerOption interface {
+	apply(*serverOptions)
+}
+
+// serverOptions holds the state configured by ServerOptions. Every Server
+// owns exactly one, built up by applying every ServerOption passed to
+// NewServer.
+type serverOptions struct {
+	recvBufferPool transport.SharedBufferPool
+}
+
+type funcServerOption struct {
+	f func(*serverOptions)
+}
+
+func (fso *funcServerOption) apply(so *serverOptions) {
+	fso.f(so)
+}
+
+func newFuncServerOption(f func(*serverOptions)) *funcServerOption {
+	return &funcServerOption{f: f}
+}
+
+// Server is a gRPC server that dispatches incoming RPCs to registered
+// services.
+type Server struct {
+	opts serverOptions
+}
+
+// NewServer creates a gRPC server with the given ServerOptions applied.
+func NewServer(opt ...ServerOption) *Server {
+	var opts serverOptions
+	for _, o := range opt {
+		o.apply(&opts)
+	}
+	return &Server{opts: opts}
+}
+
+// transportConfig returns the transport.ServerConfig that the server
+// transport for a newly accepted connection is constructed with, carrying
+// this Server's configured SharedBufferPool through to the read path (via
+// transport.ServerConfig.BufferPool, which defaults to a nopBufferPool when
+// none was configured).
+func (s *Server) transportConfig() transport.ServerConfig {
+	return transport.ServerConfig{RecvBufferPool: s.opts.recvBufferPool}
+}
+
+// RecvBufferPool returns a ServerOption that configures the server to use
+// the provided shared buffer pool for its inbound message buffers. Using a
+// shared buffer pool can significantly reduce memory allocation, especially
+// for workloads with large or bursty message sizes.
+//
+// Buffers handed out by the pool are returned (and may be reused for a
+// later message) once the codec has finished unmarshalling a given
+// message, so a codec or application must not retain a reference to the
+// unmarshaled message's backing buffer past the call to Unmarshal.
+//
+// # Experimental
+//
+// Notice: This API is EXPERIMENTAL and may be changed or removed in a
+// later release.
+func RecvBufferPool(bufferPool transport.SharedBufferPool) ServerOption {
+	return newFuncServerOption(func(o *serverOptions) {
+		o.recvBufferPool = bufferPool
+	})
+}
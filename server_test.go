@@ -0,0 +1,53 @@
+/*
+ * Copyright 2023 gRPC authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package grpc
+
+import (
+	"bytes"
+	"testing"
+
+	"google.golang.org/grpc/internal/transport"
+)
+
+func TestServerRecvBufferPoolReachesTransportConfig(t *testing.T) {
+	pool := transport.NewSharedBufferPool()
+	s := NewServer(RecvBufferPool(pool))
+
+	cfg := s.transportConfig()
+	if cfg.BufferPool() != pool {
+		t.Fatal("transportConfig().BufferPool() did not return the pool passed to RecvBufferPool")
+	}
+
+	want := []byte("server message")
+	mr := transport.NewServerMessageReader(bytes.NewReader(want), cfg)
+	got, err := mr.ReadMessage(len(want))
+	if err != nil {
+		t.Fatalf("ReadMessage() returned error: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("ReadMessage() = %q, want %q", got, want)
+	}
+	mr.FreeMessage(got)
+}
+
+func TestServerDefaultsToNopBufferPool(t *testing.T) {
+	s := NewServer()
+	if s.transportConfig().BufferPool() == nil {
+		t.Fatal("transportConfig().BufferPool() = nil, want a default nopBufferPool")
+	}
+}